@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// pageCacheEntry is a single record in a PageCache's on-disk index.
+type pageCacheEntry struct {
+	Path       string    `json:"path"`
+	Confidence float64   `json:"confidence"`
+	Saved      time.Time `json:"saved"`
+}
+
+// PageCache is a content-addressed, persistent store of OCR'd page PDFs, keyed by the
+// SHA-256 of the rendered page image plus the OCR engine used to produce it. It turns
+// the whole-file dedupe done via dupeMap into a much finer-grained, page-level dedupe:
+// re-running ripfix on the same or overlapping PDFs can skip OCR entirely for any page
+// it has already seen.
+type PageCache struct {
+	dir       string
+	indexPath string
+
+	mu    sync.Mutex
+	index map[string]pageCacheEntry
+}
+
+// newPageCache opens (or creates) a PageCache rooted at dir.
+func newPageCache(dir string) (*PageCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "locks"), 0750); err != nil {
+		return nil, fmt.Errorf("error creating cache dir '%s': %w", dir, err)
+	}
+
+	c := &PageCache{
+		dir:       dir,
+		indexPath: filepath.Join(dir, "index.json"),
+		index:     make(map[string]pageCacheEntry),
+	}
+
+	//#nosec G304 -- indexPath is derived from --cache-dir
+	b, err := os.ReadFile(c.indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("error reading cache index '%s': %w", c.indexPath, err)
+	}
+	if err := json.Unmarshal(b, &c.index); err != nil {
+		return nil, fmt.Errorf("error parsing cache index '%s': %w", c.indexPath, err)
+	}
+
+	return c, nil
+}
+
+// key returns the PageCache key for a rendered page image and the engine that will OCR it.
+func pageCacheKey(imageHash, engine string) string {
+	return imageHash + "_" + engine
+}
+
+// Get returns the cached page PDF path and confidence for key, if present.
+func (c *PageCache) Get(key string) (path string, confidence float64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.index[key]
+	if !ok {
+		return "", 0, false
+	}
+	if !fileExists(e.Path) {
+		// The cache entry is stale (e.g. the cache dir was partially cleaned); treat as a miss.
+		delete(c.index, key)
+		return "", 0, false
+	}
+	return e.Path, e.Confidence, true
+}
+
+// Put copies src into the cache under key and persists the index.
+func (c *PageCache) Put(key, src string, confidence float64) (string, error) {
+	dst := filepath.Join(c.dir, key+filepath.Ext(src))
+	if _, err := copyFile(src, dst); err != nil {
+		return "", fmt.Errorf("error copying '%s' into cache: %w", src, err)
+	}
+
+	c.mu.Lock()
+	c.index[key] = pageCacheEntry{Path: dst, Confidence: confidence, Saved: time.Now()}
+	err := c.save()
+	c.mu.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// save writes the index atomically: write to a temp file, then rename over the original.
+// Caller must hold c.mu.
+func (c *PageCache) save() error {
+	b, err := json.Marshal(c.index)
+	if err != nil {
+		return fmt.Errorf("error marshaling cache index: %w", err)
+	}
+
+	tmp := c.indexPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0640); err != nil {
+		return fmt.Errorf("error writing cache index tmp file '%s': %w", tmp, err)
+	}
+	if err := os.Rename(tmp, c.indexPath); err != nil {
+		return fmt.Errorf("error renaming cache index into place '%s': %w", c.indexPath, err)
+	}
+	return nil
+}
+
+// GetOrCompute returns the cached page PDF for key if one exists, else it calls compute
+// to produce one and stores the result. A per-key flock ensures concurrent workers
+// racing on the same key don't duplicate the OCR work: the loser blocks until the
+// winner has populated the cache, then reuses its result.
+func (c *PageCache) GetOrCompute(key string, compute func() (path string, confidence float64, err error)) (path string, confidence float64, cached bool, err error) {
+	if p, conf, ok := c.Get(key); ok {
+		return p, conf, true, nil
+	}
+
+	lockPath := filepath.Join(c.dir, "locks", key+".lock")
+	fl := flock.New(lockPath)
+	if err := fl.Lock(); err != nil {
+		return "", 0, false, fmt.Errorf("error locking cache key '%s': %w", key, err)
+	}
+	defer fl.Unlock()
+
+	// Someone may have finished the work while we waited for the lock.
+	if p, conf, ok := c.Get(key); ok {
+		return p, conf, true, nil
+	}
+
+	src, conf, err := compute()
+	if err != nil {
+		return "", 0, false, err
+	}
+	dst, err := c.Put(key, src, conf)
+	if err != nil {
+		return "", 0, false, err
+	}
+	return dst, conf, false, nil
+}
+
+// runCacheGC opens the cache at cacheDir and prunes entries older than cacheTTL, per --cache-gc.
+func runCacheGC() {
+	c, err := newPageCache(cacheDir)
+	if err != nil {
+		die("Could not open cache '%s': %s\n", cacheDir, err)
+	}
+	removed, err := c.GC(cacheTTL)
+	if err != nil {
+		die("Error pruning cache '%s': %s\n", cacheDir, err)
+	}
+	fmt.Printf("Pruned %d entries older than %s from '%s'.\n", removed, cacheTTL, cacheDir)
+}
+
+// GC removes cache entries (and their backing files) older than ttl, returning the count removed.
+func (c *PageCache) GC(ttl time.Duration) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := time.Now().Add(-ttl)
+	removed := 0
+	for key, e := range c.index {
+		if e.Saved.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("error removing cached file '%s': %w", e.Path, err)
+		}
+		delete(c.index, key)
+		removed++
+	}
+
+	if err := c.save(); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
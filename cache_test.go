@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPageCacheKey(t *testing.T) {
+	if got, want := pageCacheKey("abc123", "tesseract"), "abc123_tesseract"; got != want {
+		t.Fatalf("pageCacheKey() = %q, want %q", got, want)
+	}
+}
+
+func TestPageCacheGetOrCompute(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newPageCache(dir)
+	if err != nil {
+		t.Fatalf("newPageCache: %v", err)
+	}
+
+	src := filepath.Join(dir, "page.pdf")
+	if err := os.WriteFile(src, []byte("page one"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	calls := 0
+	compute := func() (string, float64, error) {
+		calls++
+		return src, 87.5, nil
+	}
+
+	path, conf, cached, err := c.GetOrCompute("key1", compute)
+	if err != nil {
+		t.Fatalf("GetOrCompute (miss): %v", err)
+	}
+	if cached {
+		t.Fatal("GetOrCompute (miss) reported cached=true")
+	}
+	if conf != 87.5 {
+		t.Fatalf("GetOrCompute (miss) confidence = %v, want 87.5", conf)
+	}
+	if !fileExists(path) {
+		t.Fatalf("GetOrCompute (miss) result %q does not exist", path)
+	}
+	if calls != 1 {
+		t.Fatalf("compute called %d times, want 1", calls)
+	}
+
+	path2, conf2, cached2, err := c.GetOrCompute("key1", compute)
+	if err != nil {
+		t.Fatalf("GetOrCompute (hit): %v", err)
+	}
+	if !cached2 {
+		t.Fatal("GetOrCompute (hit) reported cached=false")
+	}
+	if path2 != path || conf2 != conf {
+		t.Fatalf("GetOrCompute (hit) = (%q, %v), want (%q, %v)", path2, conf2, path, conf)
+	}
+	if calls != 1 {
+		t.Fatalf("compute called %d times after a cache hit, want still 1", calls)
+	}
+}
+
+func TestPageCacheGetOrComputeError(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newPageCache(dir)
+	if err != nil {
+		t.Fatalf("newPageCache: %v", err)
+	}
+
+	wantErr := os.ErrInvalid
+	_, _, cached, err := c.GetOrCompute("key1", func() (string, float64, error) {
+		return "", 0, wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("GetOrCompute error = %v, want %v", err, wantErr)
+	}
+	if cached {
+		t.Fatal("GetOrCompute reported cached=true on a compute error")
+	}
+}
+
+func TestPageCacheGC(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newPageCache(dir)
+	if err != nil {
+		t.Fatalf("newPageCache: %v", err)
+	}
+
+	oldSrc := filepath.Join(dir, "old.pdf")
+	newSrc := filepath.Join(dir, "new.pdf")
+	if err := os.WriteFile(oldSrc, []byte("old"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(newSrc, []byte("new"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	oldPath, err := c.Put("old-key", oldSrc, 50)
+	if err != nil {
+		t.Fatalf("Put (old): %v", err)
+	}
+	if _, err := c.Put("new-key", newSrc, 90); err != nil {
+		t.Fatalf("Put (new): %v", err)
+	}
+
+	// Backdate the old entry so it falls outside a 1-hour TTL.
+	c.mu.Lock()
+	e := c.index["old-key"]
+	e.Saved = time.Now().Add(-2 * time.Hour)
+	c.index["old-key"] = e
+	c.mu.Unlock()
+
+	removed, err := c.GC(time.Hour)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("GC removed %d entries, want 1", removed)
+	}
+	if fileExists(oldPath) {
+		t.Fatalf("GC left stale cached file %q on disk", oldPath)
+	}
+	if _, _, ok := c.Get("old-key"); ok {
+		t.Fatal("GC left the old entry in the index")
+	}
+	if _, _, ok := c.Get("new-key"); !ok {
+		t.Fatal("GC removed the entry that was still within TTL")
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cognusion/go-racket"
+)
+
+// StepName identifies which part of the pipeline a worker is currently doing.
+type StepName string
+
+const (
+	StepRip      StepName = "rip"
+	StepOCR      StepName = "ocr"
+	StepCompress StepName = "compress"
+)
+
+// go-racket's ProgressType enum is closed (it's an external module we don't own), so
+// the per-worker step/unit events --gui needs ride inside ProgressOther, exactly as
+// its own doc comment intends ("Data is to be consumed elsewhere").
+
+// stepStart signals a worker has begun step on file.
+type stepStart struct {
+	WorkerID any
+	File     string
+	Step     StepName
+}
+
+// unitDone signals a worker has completed one unit (usually a page) of step.
+type unitDone struct {
+	WorkerID any
+	Step     StepName
+	Unit     int
+	Total    int
+}
+
+// workerDone signals a worker has finished its file entirely (success or failure),
+// so --gui can drop its row.
+type workerDone struct {
+	WorkerID any
+}
+
+func progressStepStart(workerID any, file string, step StepName) racket.Progress {
+	return racket.Progress{Type: racket.ProgressOther, Data: stepStart{WorkerID: workerID, File: file, Step: step}}
+}
+
+func progressUnitDone(workerID any, step StepName, unit, total int) racket.Progress {
+	return racket.Progress{Type: racket.ProgressOther, Data: unitDone{WorkerID: workerID, Step: step, Unit: unit, Total: total}}
+}
+
+func progressWorkerDone(workerID any) racket.Progress {
+	return racket.Progress{Type: racket.ProgressOther, Data: workerDone{WorkerID: workerID}}
+}
+
+// workerRow is --gui's view of a single active worker.
+type workerRow struct {
+	File    string
+	Step    StepName
+	Unit    int
+	Total   int
+	Started time.Time
+}
+
+// runGUI replaces the single aggregate progress bar with a live, multi-row view: one
+// row per active worker (file, step, unit/total, elapsed), an overall count, and a
+// scrolling tail of recent log messages. It is the sole consumer of progressChan when
+// --gui is set, so it also does what racket.ProgressLogger would otherwise do: log
+// errors/messages and track the overall total. Closes done when progressChan closes,
+// after a final render.
+func runGUI(progressChan <-chan racket.Progress, outLog *log.Logger, logToFile bool, total int, done chan<- struct{}) {
+	const maxLogTail = 8
+
+	var (
+		mu      sync.Mutex
+		workers = make(map[string]*workerRow)
+		files   int64
+		logTail []string
+	)
+
+	render := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ids := make([]string, 0, len(workers))
+		for id := range workers {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		lines := make([]string, 0, len(ids)+len(logTail)+2)
+		lines = append(lines, fmt.Sprintf("ripfix: %d/%d files complete", files, total))
+		for _, id := range ids {
+			w := workers[id]
+			lines = append(lines, fmt.Sprintf("  [%s] %-8s %3d/%-3d %-40s %s", id, w.Step, w.Unit, w.Total, truncateMiddle(w.File, 40), time.Since(w.Started).Round(time.Second)))
+		}
+		lines = append(lines, strings.Repeat("-", 60))
+		lines = append(lines, logTail...)
+
+		for _, l := range lines {
+			fmt.Printf("\x1b[2K%s\n", l)
+		}
+		return len(lines)
+	}
+
+	redraw := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		for p := range progressChan {
+			switch p.Type {
+			case racket.ProgressError:
+				err := p.Data.(error)
+				if logToFile {
+					outLog.Printf("[PROGRESS] ERROR: %s\n", err)
+				}
+				mu.Lock()
+				logTail = appendTail(logTail, "ERROR: "+err.Error(), maxLogTail)
+				mu.Unlock()
+			case racket.ProgressMessage:
+				msg := p.Data.(string)
+				if logToFile {
+					outLog.Printf("[PROGRESS] %s\n", msg)
+				}
+				mu.Lock()
+				logTail = appendTail(logTail, msg, maxLogTail)
+				mu.Unlock()
+			case racket.ProgressUpdate:
+				mu.Lock()
+				files += p.Data.(int64)
+				mu.Unlock()
+			case racket.ProgressEstimate:
+				mu.Lock()
+				total = int(p.Data.(int64))
+				mu.Unlock()
+			case racket.ProgressOther:
+				mu.Lock()
+				switch d := p.Data.(type) {
+				case stepStart:
+					workers[fmt.Sprintf("%v", d.WorkerID)] = &workerRow{File: d.File, Step: d.Step, Started: time.Now()}
+				case unitDone:
+					if w, ok := workers[fmt.Sprintf("%v", d.WorkerID)]; ok {
+						w.Step, w.Unit, w.Total = d.Step, d.Unit, d.Total
+					}
+				case workerDone:
+					delete(workers, fmt.Sprintf("%v", d.WorkerID))
+				}
+				mu.Unlock()
+			}
+			trigger()
+		}
+		close(redraw)
+	}()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	prevLines := 0
+	for {
+		_, ok := <-merge(redraw, ticker.C)
+		if prevLines > 0 {
+			fmt.Printf("\x1b[%dA", prevLines)
+		}
+		prevLines = render()
+		if !ok {
+			close(done)
+			return
+		}
+	}
+}
+
+// merge waits for whichever of redraw or tick fires next, reporting ok=false only
+// when redraw has been closed (i.e. there will be no more progress to show).
+func merge(redraw <-chan struct{}, tick <-chan time.Time) <-chan bool {
+	out := make(chan bool, 1)
+	select {
+	case _, ok := <-redraw:
+		out <- ok
+	case <-tick:
+		out <- true
+	}
+	return out
+}
+
+func appendTail(tail []string, line string, max int) []string {
+	tail = append(tail, line)
+	if len(tail) > max {
+		tail = tail[len(tail)-max:]
+	}
+	return tail
+}
+
+func truncateMiddle(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "…" + s[len(s)-n+1:]
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileState is a step in a source PDF's processing lifecycle, as tracked by a Manifest.
+type FileState string
+
+const (
+	StateQueued     FileState = "queued"
+	StateRipped     FileState = "ripped"
+	StateOCRed      FileState = "ocred"
+	StateCompressed FileState = "compressed"
+	StateDone       FileState = "done"
+	StateFailed     FileState = "failed"
+)
+
+// StepRecord is a completed step in a ManifestEntry's history, with how long it took.
+type StepRecord struct {
+	State    FileState `json:"state"`
+	Duration string    `json:"duration"`
+}
+
+// ManifestEntry is a single source PDF's progress through the ripfix pipeline.
+type ManifestEntry struct {
+	Source        string       `json:"source"`
+	SourceSHA256  string       `json:"source_sha256,omitempty"`
+	Product       string       `json:"product,omitempty"`
+	ProductSHA256 string       `json:"product_sha256,omitempty"`
+	State         FileState    `json:"state"`
+	Error         string       `json:"error,omitempty"`
+	Steps         []StepRecord `json:"steps,omitempty"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// Manifest is a JSON record of every source PDF ripfix has seen and the state each is
+// in, so a crashed or interrupted run can be resumed with --resume instead of starting
+// over. It replaces the old "does the _fixed file exist?" skip check with an auditable,
+// per-file state machine: queued -> ripped -> ocred -> compressed -> done|failed.
+type Manifest struct {
+	Path       string `json:"-"`
+	MirrorPath string `json:"-"`
+
+	mu      sync.Mutex
+	Entries map[string]*ManifestEntry `json:"entries"`
+}
+
+// newManifest returns an empty Manifest that will be written to path.
+func newManifest(path string) *Manifest {
+	return &Manifest{
+		Path:    path,
+		Entries: make(map[string]*ManifestEntry),
+	}
+}
+
+// loadManifest reads a previously-written manifest from path, for use with --resume.
+func loadManifest(path string) (*Manifest, error) {
+	//#nosec G304 -- path comes from --resume, operator-controlled
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest '%s': %w", path, err)
+	}
+
+	m := &Manifest{Path: path, Entries: make(map[string]*ManifestEntry)}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("parsing manifest '%s': %w", path, err)
+	}
+	return m, nil
+}
+
+// entry returns the entry for source, creating a freshly-queued one if needed.
+// Callers must hold m.mu.
+func (m *Manifest) entry(source string) *ManifestEntry {
+	e, ok := m.Entries[source]
+	if !ok {
+		e = &ManifestEntry{Source: source, State: StateQueued, UpdatedAt: time.Now()}
+		m.Entries[source] = e
+	}
+	return e
+}
+
+// IsTerminal reports whether source is already in a done or failed state, per a
+// previously loaded manifest (i.e. whether --resume should skip it).
+func (m *Manifest) IsTerminal(source string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.Entries[source]
+	return ok && (e.State == StateDone || e.State == StateFailed)
+}
+
+// Enqueue (re-)marks source as queued, recording its source SHA-256.
+func (m *Manifest) Enqueue(source, sourceSHA256 string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.entry(source)
+	e.SourceSHA256 = sourceSHA256
+	e.State = StateQueued
+	e.Error = ""
+	e.UpdatedAt = time.Now()
+	return m.save()
+}
+
+// Transition advances source to state, recording how long it spent in its prior state
+// and, if stepErr is non-nil, the error that caused the transition (used for failures).
+func (m *Manifest) Transition(source string, state FileState, stepErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.entry(source)
+	now := time.Now()
+	e.Steps = append(e.Steps, StepRecord{State: state, Duration: now.Sub(e.UpdatedAt).String()})
+	e.State = state
+	e.UpdatedAt = now
+	if stepErr != nil {
+		e.Error = stepErr.Error()
+	}
+	return m.save()
+}
+
+// SetProduct records the resulting product file and its SHA-256 for source.
+func (m *Manifest) SetProduct(source, product, productSHA256 string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.entry(source)
+	e.Product = product
+	e.ProductSHA256 = productSHA256
+	return m.save()
+}
+
+// save writes the manifest to Path (and, if set, mirrors it to MirrorPath), atomically.
+// Callers must hold m.mu.
+func (m *Manifest) save() error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+
+	if err := writeFileAtomic(m.Path, b); err != nil {
+		return err
+	}
+	if m.MirrorPath != "" {
+		if err := writeFileAtomic(m.MirrorPath, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic writes b to a temp file alongside path, then renames it into place.
+func writeFileAtomic(path string, b []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0640); err != nil {
+		return fmt.Errorf("writing '%s': %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("renaming '%s' -> '%s': %w", tmp, path, err)
+	}
+	return nil
+}
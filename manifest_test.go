@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestEnqueueAndIsTerminal(t *testing.T) {
+	m := newManifest(filepath.Join(t.TempDir(), "manifest.json"))
+
+	if m.IsTerminal("a.pdf") {
+		t.Fatal("IsTerminal on an unseen source = true, want false")
+	}
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if m.IsTerminal("a.pdf") {
+		t.Fatal("IsTerminal after Enqueue = true, want false")
+	}
+	if got := m.Entries["a.pdf"].SourceSHA256; got != "deadbeef" {
+		t.Fatalf("SourceSHA256 = %q, want %q", got, "deadbeef")
+	}
+}
+
+func TestManifestTransition(t *testing.T) {
+	m := newManifest(filepath.Join(t.TempDir(), "manifest.json"))
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	for _, state := range []FileState{StateRipped, StateOCRed, StateCompressed, StateDone} {
+		if err := m.Transition("a.pdf", state, nil); err != nil {
+			t.Fatalf("Transition(%s): %v", state, err)
+		}
+	}
+
+	e := m.Entries["a.pdf"]
+	if e.State != StateDone {
+		t.Fatalf("State = %v, want %v", e.State, StateDone)
+	}
+	if len(e.Steps) != 4 {
+		t.Fatalf("len(Steps) = %d, want 4", len(e.Steps))
+	}
+	if e.Error != "" {
+		t.Fatalf("Error = %q, want empty", e.Error)
+	}
+	if !m.IsTerminal("a.pdf") {
+		t.Fatal("IsTerminal after StateDone = false, want true")
+	}
+}
+
+func TestManifestTransitionFailure(t *testing.T) {
+	m := newManifest(filepath.Join(t.TempDir(), "manifest.json"))
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	stepErr := errors.New("tesseract exploded")
+	if err := m.Transition("a.pdf", StateFailed, stepErr); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	e := m.Entries["a.pdf"]
+	if e.State != StateFailed {
+		t.Fatalf("State = %v, want %v", e.State, StateFailed)
+	}
+	if e.Error != stepErr.Error() {
+		t.Fatalf("Error = %q, want %q", e.Error, stepErr.Error())
+	}
+	if !m.IsTerminal("a.pdf") {
+		t.Fatal("IsTerminal after StateFailed = false, want true")
+	}
+}
+
+func TestManifestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m := newManifest(path)
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := m.Transition("a.pdf", StateRipped, nil); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if err := m.SetProduct("a.pdf", "a_fixed.pdf", "cafef00d"); err != nil {
+		t.Fatalf("SetProduct: %v", err)
+	}
+
+	loaded, err := loadManifest(path)
+	if err != nil {
+		t.Fatalf("loadManifest: %v", err)
+	}
+
+	e, ok := loaded.Entries["a.pdf"]
+	if !ok {
+		t.Fatal("loaded manifest is missing the 'a.pdf' entry")
+	}
+	if e.State != StateRipped {
+		t.Fatalf("loaded State = %v, want %v", e.State, StateRipped)
+	}
+	if e.Product != "a_fixed.pdf" || e.ProductSHA256 != "cafef00d" {
+		t.Fatalf("loaded Product/ProductSHA256 = %q/%q, want %q/%q", e.Product, e.ProductSHA256, "a_fixed.pdf", "cafef00d")
+	}
+}
+
+func TestManifestReEnqueueClearsError(t *testing.T) {
+	m := newManifest(filepath.Join(t.TempDir(), "manifest.json"))
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := m.Transition("a.pdf", StateFailed, errors.New("boom")); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	if err := m.Enqueue("a.pdf", "deadbeef"); err != nil {
+		t.Fatalf("re-Enqueue: %v", err)
+	}
+
+	e := m.Entries["a.pdf"]
+	if e.State != StateQueued {
+		t.Fatalf("State after re-Enqueue = %v, want %v", e.State, StateQueued)
+	}
+	if e.Error != "" {
+		t.Fatalf("Error after re-Enqueue = %q, want empty", e.Error)
+	}
+	if m.IsTerminal("a.pdf") {
+		t.Fatal("IsTerminal after re-Enqueue = true, want false")
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/cognusion/go-racket"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// RenderEngine abstracts how a PDF's pages become images, how recognized pages are
+// reassembled, and how the result is compressed, selected via --engine. This is
+// separate from OCREngine (ocr.go), which owns only the recognition step and is
+// shared across both --engine values.
+type RenderEngine interface {
+	// Prepare renders the source PDF into page images in outFolder, reporting per-page
+	// progress to progressChan (via progressUnitDone) when gui is set.
+	Prepare(pdf, outFolder string, workerID any, gui bool, progressChan chan<- racket.Progress) error
+	// Assemble merges the ordered, already-recognized single-page PDFs into the
+	// final output at outPath (outPath has no extension; ".pdf" is appended).
+	Assemble(pagePDFs []string, outPath string) error
+	// Compress produces a smaller pdfout from pdfin, targeting the given --compress style.
+	Compress(style, pdfin, pdfout string) error
+}
+
+// newRenderEngine returns the RenderEngine registered under name.
+func newRenderEngine(name string) (RenderEngine, error) {
+	switch name {
+	case "external", "":
+		return externalRenderEngine{}, nil
+	case "native":
+		return nativeRenderEngine{}, nil
+	default:
+		return nil, fmt.Errorf("unknown render engine %q", name)
+	}
+}
+
+// externalRenderEngine is the default RenderEngine, shelling out to pdftoppm, pdfunite,
+// and ps2pdf, same as ripfix has always done.
+type externalRenderEngine struct{}
+
+func (externalRenderEngine) Prepare(pdf, outFolder string, workerID any, gui bool, progressChan chan<- racket.Progress) error {
+	return pdfToTiff(pdf, outFolder, workerID, gui, progressChan)
+}
+
+func (externalRenderEngine) Assemble(pagePDFs []string, outPath string) error {
+	return pdfUnite(pagePDFs, outPath+".pdf")
+}
+
+func (externalRenderEngine) Compress(style, pdfin, pdfout string) error {
+	return compressPdf(style, pdfin, pdfout)
+}
+
+// nativeRenderEngine is a pure-Go RenderEngine for machines without poppler/ghostscript
+// installed. It only supports image-based (e.g. scanned) PDFs: rather than rasterizing
+// each page, Prepare dumps each page's single largest embedded image, so text/vector
+// PDFs -- which have no embedded page image -- aren't supported; use --engine=external
+// for those.
+type nativeRenderEngine struct{}
+
+func (nativeRenderEngine) Prepare(pdf, outFolder string, workerID any, gui bool, progressChan chan<- racket.Progress) error {
+	f, err := os.Open(pdf) //#nosec G304 -- pdf comes from --pdfs, operator-controlled
+	if err != nil {
+		return fmt.Errorf("opening '%s': %w", pdf, err)
+	}
+	defer f.Close()
+
+	type rawPage struct {
+		ext  string
+		data []byte
+	}
+	byPage := make(map[int]rawPage)
+
+	digest := func(img model.Image, _ bool, _ int) error {
+		b, rErr := io.ReadAll(img)
+		if rErr != nil {
+			return rErr
+		}
+		// A page may embed more than one image (e.g. a logo); keep only the
+		// largest, on the assumption it's the scanned page itself.
+		if existing, ok := byPage[img.PageNr]; !ok || len(b) > len(existing.data) {
+			byPage[img.PageNr] = rawPage{ext: img.FileType, data: b}
+		}
+		return nil
+	}
+
+	if err := api.ExtractImages(f, nil, digest, nil); err != nil {
+		return fmt.Errorf("extracting images from '%s': %w", pdf, err)
+	}
+	if len(byPage) == 0 {
+		return fmt.Errorf("'%s' has no embedded page images; native rendering only supports image-based/scanned PDFs -- try --engine=external", pdf)
+	}
+
+	count, cErr := api.PageCountFile(pdf)
+	if cErr != nil {
+		return fmt.Errorf("counting pages in '%s': %w", pdf, cErr)
+	}
+	if len(byPage) != count {
+		// A page with no embedded image at all (e.g. a blank separator page) is just as
+		// silently dropped from byPage as an unrecognized image type -- fail loudly
+		// instead of assembling a PDF that's missing pages.
+		return fmt.Errorf("'%s' has %d pages but only %d have embedded images; native rendering only supports fully image-based/scanned PDFs -- try --engine=external", pdf, count, len(byPage))
+	}
+
+	pages := make([]int, 0, len(byPage))
+	for p := range byPage {
+		pages = append(pages, p)
+	}
+	sort.Ints(pages)
+	width := len(strconv.Itoa(pages[len(pages)-1]))
+
+	for i, p := range pages {
+		rp := byPage[p]
+		name := fmt.Sprintf("%spage-%0*d.%s", outFolder, width, p, rp.ext)
+		if err := os.WriteFile(name, rp.data, 0640); err != nil {
+			return fmt.Errorf("writing '%s': %w", name, err)
+		}
+		if gui {
+			progressChan <- progressUnitDone(workerID, StepRip, i+1, len(pages))
+		}
+	}
+	return nil
+}
+
+func (nativeRenderEngine) Assemble(pagePDFs []string, outPath string) error {
+	return api.MergeCreateFile(pagePDFs, outPath+".pdf", false, nil)
+}
+
+// Compress re-encodes pdfin's embedded page images as JPEG at a quality derived from
+// style, replacing each image object in place -- everything else, notably the OCR text
+// layer tesseract added, is left untouched. This is the native equivalent of ps2pdf's
+// -dPDFSETTINGS presets, without ghostscript.
+func (nativeRenderEngine) Compress(style, pdfin, pdfout string) error {
+	if _, err := copyFile(pdfin, pdfout); err != nil {
+		return fmt.Errorf("copying '%s' -> '%s': %w", pdfin, pdfout, err)
+	}
+
+	f, err := os.Open(pdfin) //#nosec G304 -- pdfin is a ripfix-produced temp file
+	if err != nil {
+		return fmt.Errorf("opening '%s': %w", pdfin, err)
+	}
+
+	type imgRef struct {
+		objNr int
+		data  []byte
+	}
+	var imgs []imgRef
+	digest := func(img model.Image, _ bool, _ int) error {
+		b, rErr := io.ReadAll(img)
+		if rErr != nil {
+			return rErr
+		}
+		imgs = append(imgs, imgRef{objNr: img.ObjNr, data: b})
+		return nil
+	}
+	if err := api.ExtractImages(f, nil, digest, nil); err != nil {
+		f.Close()
+		return fmt.Errorf("extracting images from '%s': %w", pdfin, err)
+	}
+	f.Close()
+
+	quality := jpegQuality(style)
+	for _, ir := range imgs {
+		decoded, _, dErr := image.Decode(bytes.NewReader(ir.data))
+		if dErr != nil {
+			// Not a format we can decode/re-encode (e.g. an embedded TIFF); leave as-is.
+			continue
+		}
+
+		tmpImg, tErr := os.CreateTemp("", "ripfix-native-*.jpg")
+		if tErr != nil {
+			return fmt.Errorf("creating temp image: %w", tErr)
+		}
+		if eErr := jpeg.Encode(tmpImg, decoded, &jpeg.Options{Quality: quality}); eErr != nil {
+			tmpImg.Close()
+			os.Remove(tmpImg.Name())
+			return fmt.Errorf("encoding object %d: %w", ir.objNr, eErr)
+		}
+		tmpImg.Close()
+
+		uErr := api.UpdateImagesFile(pdfout, tmpImg.Name(), pdfout, ir.objNr, 0, "", nil)
+		os.Remove(tmpImg.Name())
+		if uErr != nil {
+			return fmt.Errorf("updating image object %d in '%s': %w", ir.objNr, pdfout, uErr)
+		}
+	}
+	return nil
+}
+
+// jpegQuality maps a --compress style to a JPEG quality, mirroring ps2pdf's presets:
+// screen/ebook trade quality for size more aggressively than print-grade "none".
+func jpegQuality(style string) int {
+	switch style {
+	case "screen":
+		return 40
+	case "ebook":
+		return 60
+	default:
+		return 90
+	}
+}
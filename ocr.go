@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cognusion/go-racket"
+)
+
+// OCREngine abstracts the page recognition step that used to be a hardcoded shell-out
+// to tesseract, so other backends (an HTTP-callable OCR service, a stub for testing,
+// etc) can be swapped in via --ocr-engine. Rendering a PDF's pages into images and
+// reassembling/compressing the recognized result is a separate concern, handled by
+// a RenderEngine (see native.go) selected independently via --engine.
+type OCREngine interface {
+	// RecognizePage OCRs a single page image, returning the path to a single-page
+	// searchable PDF and its mean word confidence (0-100).
+	RecognizePage(image string) (pdfPath string, confidence float64, err error)
+}
+
+// Preprocessor binarizes a page image at a given threshold (0-1), returning the path
+// to the new variant.
+type Preprocessor interface {
+	Binarize(image string, threshold float64) (string, error)
+}
+
+// newOCREngine returns the OCREngine registered under name.
+func newOCREngine(name string) (OCREngine, error) {
+	switch name {
+	case "tesseract", "":
+		return tesseractEngine{}, nil
+	case "stub":
+		return stubEngine{}, nil
+	default:
+		return nil, fmt.Errorf("unknown OCR engine %q", name)
+	}
+}
+
+// parseThresholds parses a comma-separated list of binarization thresholds, e.g. "0.1,0.2,0.3".
+func parseThresholds(s string) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	thresholds := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		t, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %w", p, err)
+		}
+		if t <= 0 || t >= 1 {
+			return nil, fmt.Errorf("threshold %v out of range (0,1)", t)
+		}
+		thresholds = append(thresholds, t)
+	}
+	return thresholds, nil
+}
+
+// recognizePage OCRs image with engine, transparently consulting cache (keyed on the
+// image's SHA-256 plus engineName) if one is configured. It returns the resulting
+// single-page PDF path and mean word confidence.
+func recognizePage(engine OCREngine, engineName, image string, cache *PageCache) (string, float64, error) {
+	if cache == nil {
+		return engine.RecognizePage(image)
+	}
+
+	hash, err := calculateSHA256Sum(image)
+	if err != nil {
+		return "", 0, fmt.Errorf("hashing '%s': %w", image, err)
+	}
+
+	path, conf, _, err := cache.GetOrCompute(pageCacheKey(hash, engineName), func() (string, float64, error) {
+		return engine.RecognizePage(image)
+	})
+	return path, conf, err
+}
+
+// bestThresholdPagePDFs preprocesses each page across preprocessThresholds, OCRing every
+// variant (plus the original) and keeping whichever single-page PDF has the highest mean
+// word confidence.
+func bestThresholdPagePDFs(engine OCREngine, engineName string, pages []string, cache *PageCache, workerID any, gui bool, progressChan chan<- racket.Progress) ([]string, error) {
+	pre := imageMagickPreprocessor{}
+	winners := make([]string, len(pages))
+
+	for i, page := range pages {
+		bestPath, bestConf, err := recognizePage(engine, engineName, page, cache)
+		if err != nil {
+			return nil, fmt.Errorf("recognize '%s': %w", page, err)
+		}
+
+		for _, t := range preprocessThresholds {
+			variant, bErr := pre.Binarize(page, t)
+			if bErr != nil {
+				return nil, fmt.Errorf("binarize '%s' @ %.2f: %w", page, t, bErr)
+			}
+			vPath, vConf, rErr := recognizePage(engine, engineName, variant, cache)
+			if rErr != nil {
+				return nil, fmt.Errorf("recognize '%s': %w", variant, rErr)
+			}
+			if vConf > bestConf {
+				bestConf, bestPath = vConf, vPath
+			}
+		}
+
+		progressChan <- racket.PMessagef("[WORKER %v] page %d: best variant confidence %.1f", workerID, i, bestConf)
+		winners[i] = bestPath
+		if gui {
+			progressChan <- progressUnitDone(workerID, StepOCR, i+1, len(pages))
+		}
+	}
+
+	return winners, nil
+}
+
+// tesseractEngine is the default OCREngine, shelling out to tesseract.
+type tesseractEngine struct{}
+
+func (tesseractEngine) RecognizePage(image string) (string, float64, error) {
+	base := strings.TrimSuffix(image, filepath.Ext(image))
+	// tesseract can emit both outputs from one pass: the tsv for confidence scoring,
+	// and the single-page searchable pdf itself.
+	if err := simpleRun("tesseract", image, base, "tsv", "pdf"); err != nil {
+		return "", 0, fmt.Errorf("tesseract '%s': %w", image, err)
+	}
+	defer os.Remove(base + ".tsv")
+
+	conf, err := meanWordConfidence(base + ".tsv")
+	if err != nil {
+		return "", 0, err
+	}
+	return base + ".pdf", conf, nil
+}
+
+// meanWordConfidence parses a tesseract TSV file and returns the mean confidence
+// across recognized words (rows with conf >= 0).
+func meanWordConfidence(tsvPath string) (float64, error) {
+	//#nosec G304 -- tsvPath is a tesseract-generated temp file
+	f, err := os.Open(tsvPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open tsv: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		sum   float64
+		count int
+	)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header row
+	for scanner.Scan() {
+		cols := strings.Split(scanner.Text(), "\t")
+		if len(cols) < 11 {
+			continue
+		}
+		conf, cErr := strconv.ParseFloat(cols[10], 64)
+		if cErr != nil || conf < 0 {
+			continue
+		}
+		sum += conf
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read tsv: %w", err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return sum / float64(count), nil
+}
+
+// stubEngine is an OCREngine that does no real recognition. It is useful for testing
+// the pipeline (flags, dedupe, caching, resumption, etc) without tesseract installed.
+type stubEngine struct{}
+
+func (stubEngine) RecognizePage(image string) (string, float64, error) {
+	// Nothing to recognize; pretend the rendered page image is already the "page PDF".
+	return image, 100, nil
+}
+
+// imageMagickPreprocessor binarizes images by shelling out to ImageMagick's convert.
+type imageMagickPreprocessor struct{}
+
+func (imageMagickPreprocessor) Binarize(image string, threshold float64) (string, error) {
+	outPath := fmt.Sprintf("%s.t%g.tif", strings.TrimSuffix(image, filepath.Ext(image)), threshold)
+	pct := fmt.Sprintf("%.0f%%", threshold*100)
+	if err := simpleRun("convert", image, "-threshold", pct, outPath); err != nil {
+		return "", fmt.Errorf("convert threshold '%s' -> '%s': %w", image, outPath, err)
+	}
+	return outPath, nil
+}
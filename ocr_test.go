@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []float64
+		wantErr bool
+	}{
+		{name: "default", in: "0.1,0.2,0.3", want: []float64{0.1, 0.2, 0.3}},
+		{name: "single", in: "0.5", want: []float64{0.5}},
+		{name: "whitespace", in: " 0.1 , 0.2 ", want: []float64{0.1, 0.2}},
+		{name: "not a number", in: "0.1,nope", wantErr: true},
+		{name: "zero out of range", in: "0", wantErr: true},
+		{name: "one out of range", in: "1", wantErr: true},
+		{name: "negative out of range", in: "-0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseThresholds(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseThresholds(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseThresholds(%q) unexpected error: %v", tt.in, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseThresholds(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("parseThresholds(%q)[%d] = %v, want %v", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMeanWordConfidence(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []string // tsv data rows, header is added automatically
+		want float64
+	}{
+		{
+			name: "mix of confidences, ignoring negative (non-word) rows",
+			rows: []string{
+				"5\t1\t1\t1\t1\t1\t0\t0\t0\t0\t90\ttext",
+				"5\t1\t1\t1\t1\t2\t0\t0\t0\t0\t-1\t",
+				"5\t1\t1\t1\t1\t3\t0\t0\t0\t0\t70\tmore",
+			},
+			want: 80,
+		},
+		{
+			name: "no recognized words",
+			rows: []string{
+				"5\t1\t1\t1\t1\t1\t0\t0\t0\t0\t-1\t",
+			},
+			want: 0,
+		},
+		{
+			name: "short row is ignored",
+			rows: []string{
+				"5\t1\t1",
+				"5\t1\t1\t1\t1\t1\t0\t0\t0\t0\t60\tword",
+			},
+			want: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "page.tsv")
+			content := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" + strings.Join(tt.rows, "\n") + "\n"
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			got, err := meanWordConfidence(path)
+			if err != nil {
+				t.Fatalf("meanWordConfidence: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("meanWordConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeanWordConfidenceMissingFile(t *testing.T) {
+	if _, err := meanWordConfidence(filepath.Join(t.TempDir(), "missing.tsv")); err == nil {
+		t.Fatal("meanWordConfidence on a missing file: expected error, got nil")
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Opener abstracts reading a PDF from wherever it lives -- a local path, or a
+// http(s)/s3/sftp URL -- so --pdfs can mix globs and remote sources.
+type Opener interface {
+	// Open returns a reader for src. The caller must Close it when done.
+	Open(src string) (io.ReadCloser, error)
+}
+
+// Uploader is the symmetric counterpart to Opener, used when --out is a URL.
+type Uploader interface {
+	// Put uploads the local file at src to dest.
+	Put(src, dest string) error
+}
+
+// remoteScheme returns the URL scheme of src ("http", "s3", "sftp", etc), or ""
+// if src looks like a local path.
+func remoteScheme(src string) string {
+	if !strings.Contains(src, "://") {
+		// Requiring "://" keeps local filenames with a colon before their first slash
+		// (e.g. "invoice:2024-01.pdf") from being misparsed as a URL scheme.
+		return ""
+	}
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// len==1 excludes things like Windows drive letters (C:\...), not that we expect any.
+		return ""
+	}
+	return u.Scheme
+}
+
+// newOpener returns the Opener registered for scheme.
+func newOpener(scheme string) (Opener, error) {
+	switch scheme {
+	case "file":
+		return fileOpener{}, nil
+	case "http", "https":
+		return httpOpener{}, nil
+	case "s3":
+		return commandOpener{name: "aws", args: func(src string) []string { return []string{"s3", "cp", src, "-"} }}, nil
+	case "sftp":
+		return commandOpener{name: "curl", args: func(src string) []string { return []string{"-fsSL", src} }}, nil
+	default:
+		return nil, fmt.Errorf("unsupported source scheme %q", scheme)
+	}
+}
+
+// newUploader returns the Uploader registered for scheme.
+func newUploader(scheme string) (Uploader, error) {
+	switch scheme {
+	case "http", "https":
+		return httpUploader{}, nil
+	case "s3":
+		return commandUploader{name: "aws", args: func(src, dest string) []string { return []string{"s3", "cp", src, dest} }}, nil
+	case "sftp":
+		return commandUploader{name: "curl", args: func(src, dest string) []string { return []string{"-fsSL", "-T", src, dest} }}, nil
+	default:
+		return nil, fmt.Errorf("unsupported destination scheme %q", scheme)
+	}
+}
+
+// requireRemoteTools checks that any external CLI tools needed by the given sources
+// and destination are on PATH, dying with a clear message if not. http(s) sources and
+// destinations use net/http directly, so only s3 (aws) and sftp (curl) need checking.
+func requireRemoteTools(pdfs []string, out string) {
+	need := make(map[string]bool)
+	for _, s := range append(append([]string{}, pdfs...), out) {
+		switch remoteScheme(s) {
+		case "s3":
+			need["aws"] = true
+		case "sftp":
+			need["curl"] = true
+		}
+	}
+	for tool := range need {
+		if _, err := exec.LookPath(tool); err != nil {
+			die("Could not find path to %s, needed for s3/sftp sources or destinations!\n", tool)
+		}
+	}
+}
+
+// downloadPDF opens src (a remote URL) and writes it to destDir, returning the
+// local path it was saved to.
+func downloadPDF(src, destDir string) (string, error) {
+	opener, err := newOpener(remoteScheme(src))
+	if err != nil {
+		return "", err
+	}
+
+	rc, err := opener.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("opening '%s': %w", src, err)
+	}
+	defer rc.Close()
+
+	u, uErr := url.Parse(src)
+	if uErr != nil {
+		return "", fmt.Errorf("parsing '%s': %w", src, uErr)
+	}
+	dest := filepath.Join(destDir, filepath.Base(u.Path))
+
+	f, err := os.Create(dest) //#nosec G304 -- dest is derived from our own downloadDir
+	if err != nil {
+		return "", fmt.Errorf("creating '%s': %w", dest, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return "", fmt.Errorf("downloading '%s' -> '%s': %w", src, dest, err)
+	}
+
+	return dest, nil
+}
+
+// uploadProduct uploads localPath to remoteOutBase (a URL, e.g. "s3://bucket/prefix/"),
+// appending localPath's basename.
+func uploadProduct(localPath, remoteOutBase string) error {
+	uploader, err := newUploader(remoteScheme(remoteOutBase))
+	if err != nil {
+		return err
+	}
+	dest := remoteOutBase + filepath.Base(localPath)
+	if err := uploader.Put(localPath, dest); err != nil {
+		return fmt.Errorf("uploading '%s' -> '%s': %w", localPath, dest, err)
+	}
+	return nil
+}
+
+// fileOpener opens a local path, tolerating an explicit "file://" scheme.
+type fileOpener struct{}
+
+func (fileOpener) Open(src string) (io.ReadCloser, error) {
+	return os.Open(strings.TrimPrefix(src, "file://")) //#nosec G304 -- src comes from --pdfs, operator-controlled
+}
+
+// httpOpener fetches a PDF over http(s).
+type httpOpener struct{}
+
+func (httpOpener) Open(src string) (io.ReadCloser, error) {
+	//#nosec G107 -- src comes from --pdfs, operator-controlled
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET '%s': unexpected status %s", src, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// httpUploader PUTs a product file over http(s).
+type httpUploader struct{}
+
+func (httpUploader) Put(src, dest string) error {
+	f, err := os.Open(src) //#nosec G304 -- src is a product file we just created
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequest(http.MethodPut, dest, f)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("PUT '%s': unexpected status %s", dest, resp.Status)
+	}
+	return nil
+}
+
+// commandOpener opens a source by shelling out to an external tool (the aws or curl
+// CLIs, for s3:// and sftp:// respectively) and streaming its stdout.
+type commandOpener struct {
+	name string
+	args func(src string) []string
+}
+
+func (c commandOpener) Open(src string) (io.ReadCloser, error) {
+	cmd := exec.Command(c.name, c.args(src)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting '%s': %w", c.name, err)
+	}
+	return &cmdReadCloser{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+// cmdReadCloser waits on the backing command's completion when Closed.
+type cmdReadCloser struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (c *cmdReadCloser) Close() error {
+	if err := c.ReadCloser.Close(); err != nil {
+		return err
+	}
+	return c.cmd.Wait()
+}
+
+// commandUploader uploads a product by shelling out to an external tool (the aws or
+// curl CLIs, for s3:// and sftp:// respectively).
+type commandUploader struct {
+	name string
+	args func(src, dest string) []string
+}
+
+func (c commandUploader) Put(src, dest string) error {
+	return simpleRun(c.name, c.args(src, dest)...)
+}
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"bufio"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +10,8 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/cognusion/go-racket"
 	"github.com/cognusion/go-sequence"
 	"github.com/gofrs/flock"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/spf13/pflag"
 )
 
@@ -39,15 +41,29 @@ var (
 	flockFile    string
 	skipFlock    bool
 	useBar       bool
+	guiMode      bool
 	logFile      string
 	debug        bool
 	dupes        bool
 	dupeMap      sync.Map
+
+	ocrEngine              string
+	preprocess             bool
+	preprocessThresholdStr string
+	preprocessThresholds   []float64
+
+	cacheDir string
+	cacheGC  bool
+	cacheTTL time.Duration
+
+	resumeManifest string
+
+	renderEngine string
 )
 
 func init() {
-	pflag.StringSliceVarP(&pdfs, "pdfs", "p", make([]string, 0), "List of PDFs to convert. Globs are fine. Quotes are encouraged.")
-	pflag.StringVarP(&out, "out", "o", "./", "Location to place the final products. They will have the same file name as the source.")
+	pflag.StringSliceVarP(&pdfs, "pdfs", "p", make([]string, 0), "List of PDFs to convert. Globs are fine, as are s3://, sftp://, and http(s):// URLs. Quotes are encouraged.")
+	pflag.StringVarP(&out, "out", "o", "./", "Location to place the final products. They will have the same file name as the source. May be a local folder or an s3://, sftp://, or http(s):// URL.")
 	pflag.StringVarP(&tmp, "temp", "t", os.TempDir()+"/", "Location for temp files.")
 	pflag.IntVarP(&maxP, "max", "m", runtime.NumCPU(), "Maximum number of simultaneous processors.")
 	pflag.BoolVar(&clean, "clean", true, "Remove temp folders/files when complete.")
@@ -57,13 +73,36 @@ func init() {
 	pflag.StringVar(&flockFile, "flock", os.TempDir()+"/ripfix.lock", "Location of a file lock file, to ensure two copies of ripfix aren't running at the same time.")
 	pflag.BoolVar(&skipFlock, "ignore-flock", false, "DANGER: If true, skips flocking.")
 	pflag.BoolVarP(&useBar, "bar", "b", false, "Enable progress bar, suppress normal non-error screen logging.")
+	pflag.BoolVar(&guiMode, "gui", false, "Enable a live, multi-row terminal view (one row per active worker, plus an overall count and a scrolling log) instead of the aggregate bar or normal logging.")
 	pflag.StringVarP(&logFile, "log", "l", "", "If set, normal screen logging will go to the file instead, including when used with --bar.")
 	pflag.BoolVar(&debug, "debug", false, "Enables debug logging. Disables bar.")
 	pflag.BoolVar(&dupes, "dupes", false, "Enables deduplication. Every file processed gets a sha256 hash, and if a dupe is found the subsequents are skipped.")
+	pflag.StringVar(&ocrEngine, "ocr-engine", "tesseract", "OCR engine to use for recognition/assembly. One of 'tesseract' or 'stub'.")
+	pflag.BoolVar(&preprocess, "preprocess", false, "Binarize each page at several thresholds and keep the variant with the highest mean word confidence.")
+	pflag.StringVar(&preprocessThresholdStr, "preprocess-thresholds", "0.1,0.2,0.3", "Comma-separated list of binarization thresholds (0-1) to try when --preprocess is set.")
+	pflag.StringVar(&cacheDir, "cache-dir", "", "Directory for a persistent, page-level OCR result cache, keyed by page image sha256. Disabled if unset.")
+	pflag.BoolVar(&cacheGC, "cache-gc", false, "Prune cache entries older than --cache-ttl from --cache-dir, then exit.")
+	pflag.DurationVar(&cacheTTL, "cache-ttl", 30*24*time.Hour, "Maximum age of a cache entry before --cache-gc prunes it.")
+	pflag.StringVar(&resumeManifest, "resume", "", "Path to a previous run's manifest.json. Files already in a 'done' or 'failed' state will not be re-enqueued.")
+	pflag.StringVar(&renderEngine, "engine", "external", "Rendering/assembly/compression engine. 'external' (default) shells to pdftoppm/pdfunite/ps2pdf. 'native' is pure Go, for machines without poppler/ghostscript, but only supports image-based/scanned PDFs.")
 
 	pflag.CommandLine.MarkHidden("ignore-flock")
+}
+
+// parseFlags parses the command line and validates/normalizes the result, exiting the
+// process on bad input. It's kept out of init() (which only registers flags) so that
+// importing this package -- e.g. from tests -- doesn't itself parse os.Args or exit.
+func parseFlags() {
 	pflag.Parse()
 
+	if cacheGC {
+		if cacheDir == "" {
+			die("--cache-gc requires --cache-dir\n")
+		}
+		runCacheGC()
+		os.Exit(0)
+	}
+
 	if len(pdfs) == 0 {
 		fmt.Println("ripfix options:")
 		pflag.PrintDefaults()
@@ -73,6 +112,10 @@ func init() {
 	// Sanity!
 	if debug {
 		useBar = false
+		guiMode = false
+	}
+	if guiMode {
+		useBar = false
 	}
 	if reprocess {
 		// reprocess overrides the skip
@@ -93,9 +136,34 @@ func init() {
 		pflag.PrintDefaults()
 		os.Exit(1)
 	}
+	if _, err := newOCREngine(ocrEngine); err != nil {
+		fmt.Printf("OCR engine invalid: %s\n", err)
+		pflag.PrintDefaults()
+		os.Exit(1)
+	}
+	if _, err := newRenderEngine(renderEngine); err != nil {
+		fmt.Printf("Render engine invalid: %s\n", err)
+		pflag.PrintDefaults()
+		os.Exit(1)
+	}
+	if scheme := remoteScheme(out); scheme != "" {
+		if _, err := newUploader(scheme); err != nil {
+			die("--out scheme invalid: %s\n", err)
+		}
+	}
+	if preprocess {
+		thresholds, terr := parseThresholds(preprocessThresholdStr)
+		if terr != nil {
+			fmt.Printf("Preprocess thresholds invalid: %s\n", terr)
+			pflag.PrintDefaults()
+			os.Exit(1)
+		}
+		preprocessThresholds = thresholds
+	}
 }
 
 func main() {
+	parseFlags()
 
 	var (
 		pid         = os.Getpid()
@@ -164,24 +232,57 @@ func main() {
 		outLog = log.New(f, "", log.LstdFlags)
 	}
 
-	// Check for pdftoppm, tesseract, and possibly ps2pdf
-	if _, err := exec.LookPath("pdftoppm"); err != nil {
-		die("Could not find path to pdftoppm!\n")
+	// Check for pdftoppm/pdfunite/ps2pdf (only needed by --engine=external), and
+	// whatever the chosen OCR engine and possibly --preprocess need.
+	if renderEngine == "external" {
+		if _, err := exec.LookPath("pdftoppm"); err != nil {
+			die("Could not find path to pdftoppm!\n")
+		}
+		if _, err := exec.LookPath("pdfunite"); err != nil {
+			die("Could not find path to pdfunite!\n")
+		}
+		if compress != "none" {
+			if _, err := exec.LookPath("ps2pdf"); err != nil {
+				die("Could not find path to ps2pdf!\n")
+			}
+		}
 	}
-	if _, err := exec.LookPath("tesseract"); err != nil {
-		die("Could not find path to tesseract!\n")
+	if ocrEngine == "tesseract" {
+		if _, err := exec.LookPath("tesseract"); err != nil {
+			die("Could not find path to tesseract!\n")
+		}
 	}
-	if compress != "none" {
-		if _, err := exec.LookPath("ps2pdf"); err != nil {
-			die("Could not find path to ps2pdf!\n")
+	if preprocess {
+		if _, err := exec.LookPath("convert"); err != nil {
+			die("Could not find path to convert!\n")
 		}
 	}
+	requireRemoteTools(pdfs, out)
+
+	// out may be a local folder, or a URL to upload products to. If it's the latter,
+	// products are staged locally in tmp, then uploaded per-file.
+	var (
+		localOut  = out
+		remoteOut string
+	)
+	if scheme := remoteScheme(out); scheme != "" {
+		remoteOut = out
+		localOut = fmt.Sprintf("%s%s/out/", tmp, tmpFolder)
+	}
 
-	// Confirm out is a folder
-	if s, serr := os.Stat(out); serr != nil {
+	// Confirm localOut is a folder
+	if terr := os.MkdirAll(localOut, 0750); terr != nil {
+		panic(terr)
+	} else if s, serr := os.Stat(localOut); serr != nil {
 		panic(serr)
 	} else if !s.IsDir() {
-		die("Output location '%s' is not a directory.\n", out)
+		die("Output location '%s' is not a directory.\n", localOut)
+	}
+
+	// downloadDir is where remote --pdfs sources are staged locally before processing.
+	downloadDir := fmt.Sprintf("%s%s/downloads/", tmp, tmpFolder)
+	if derr := os.MkdirAll(downloadDir, 0750); derr != nil {
+		panic(derr)
 	}
 
 	// Ensure the base tmp folder is available
@@ -192,6 +293,36 @@ func main() {
 		defer os.RemoveAll(tmp + tmpFolder)
 	}
 
+	// Set up the job manifest: either a fresh one for this run, or a previous run's
+	// to resume, mirrored into localOut so operators always have a copy alongside the products.
+	manifestPath := fmt.Sprintf("%s%s/%d/manifest.json", tmp, tmpFolder, pid)
+	if merr := os.MkdirAll(fmt.Sprintf("%s%s/%d/", tmp, tmpFolder, pid), 0750); merr != nil {
+		panic(merr)
+	}
+
+	var manifest *Manifest
+	if resumeManifest != "" {
+		m, lErr := loadManifest(resumeManifest)
+		if lErr != nil {
+			die("Could not load manifest '%s': %s\n", resumeManifest, lErr)
+		}
+		m.Path = resumeManifest
+		manifest = m
+	} else {
+		manifest = newManifest(manifestPath)
+	}
+	manifest.MirrorPath = localOut + "manifest.json"
+
+	// If caching is enabled, open (or create) the page cache once, shared by all workers.
+	var cache *PageCache
+	if cacheDir != "" {
+		c, cErr := newPageCache(cacheDir)
+		if cErr != nil {
+			panic(cErr)
+		}
+		cache = c
+	}
+
 	// Oy! No printing other than to logs from this point!
 	debugLog.Printf("RipFix job starting...\n")
 
@@ -200,28 +331,56 @@ func main() {
 	// after the work is assigned.
 	rfJob := racket.NewJob(ripFixWorkFunc)
 	progressChan, doneFunc := rfJob.Supervisor(maxP, workChan)
-	defer close(progressChan)
 
 	debugLog.Printf("\tSupervisor running...\n")
 
-	go racket.ProgressLogger(outLog, logMessages, nil, progressChan, barChan)
+	// In --gui mode we're the sole consumer of progressChan, doing everything
+	// racket.ProgressLogger would (logging, tracking the overall total) plus rendering
+	// the per-worker rows. Otherwise, the usual logger (optionally paired with --bar).
+	var guiDone chan struct{}
+	if guiMode {
+		guiDone = make(chan struct{})
+		go runGUI(progressChan, outLog, logMessages, len(pdfs), guiDone)
+	} else {
+		go racket.ProgressLogger(outLog, logMessages, nil, progressChan, barChan)
+	}
 
 	debugLog.Printf("\tProcessLogger running...\n")
 
 	// Step 1 build work and dole it out
-	for _, file := range buildList(pdfs, barChan, progressChan) {
+	for _, file := range buildList(pdfs, downloadDir, barChan, progressChan) {
+		if resumeManifest != "" && manifest.IsTerminal(file) {
+			progressChan <- racket.PMessagef("[MANIFEST] '%s' already done or failed, skipping (--resume)", file)
+			continue
+		}
+
+		hash, hErr := calculateSHA256Sum(file)
+		if hErr != nil {
+			panic(hErr)
+		}
+		if err := manifest.Enqueue(file, hash); err != nil {
+			panic(err)
+		}
+
 		id := seq.NextHashID()
 		//outLog.Printf("[WORKFILE] %s is %s\n", file, id)
 		workChan <- racket.NewWork(map[string]any{
 			"id":           id,
 			"pdf":          file,
 			"temp":         fmt.Sprintf("%s%s/%d.%s/", tmp, tmpFolder, pid, id),
-			"out":          out,
+			"out":          localOut,
+			"remoteOut":    remoteOut,
 			"compress":     compress,
 			"skipExisting": skipExisting,
 			"reprocess":    reprocess,
 			"clean":        clean,
 			"dupes":        dupes,
+			"ocrEngine":    ocrEngine,
+			"preprocess":   preprocess,
+			"cache":        cache,
+			"manifest":     manifest,
+			"gui":          guiMode,
+			"engine":       renderEngine,
 		})
 	}
 	// POST: each work{} has been consumed by a worker.
@@ -232,6 +391,11 @@ func main() {
 
 	// wait until all of the workers are done
 	<-rfJob.IsDone()
+	close(progressChan)
+	if guiMode {
+		// Let the final render flush before we tear down.
+		<-guiDone
+	}
 	debugLog.Printf("\tJob is done!\n")
 }
 
@@ -241,10 +405,46 @@ func ripFixWorkFunc(id any, w racket.Work, progressChan chan<- racket.Progress)
 	var (
 		err         error
 		productFile string
+		uploaded    bool
 	)
 
 	progressChan <- racket.PMessagef("[WORKER %v] Work! %+v", id, w)
 
+	pdfSrc := w.GetString("pdf")
+	manifest, _ := w.Get("manifest").(*Manifest)
+
+	// Registered before the manifest/dupes defers below so it runs last: defers run
+	// LIFO, and productFile must still exist on disk when those record its hash and
+	// copy it to dupes, which only happens once this is the outermost defer.
+	if remoteOut := w.GetString("remoteOut"); remoteOut != "" && w.GetBool("clean") {
+		defer func() {
+			if uploaded {
+				os.Remove(productFile)
+			}
+		}()
+	}
+
+	if w.GetBool("gui") {
+		defer func() { progressChan <- progressWorkerDone(id) }()
+	}
+	defer func() {
+		if manifest == nil {
+			return
+		}
+		if err != nil {
+			if mErr := manifest.Transition(pdfSrc, StateFailed, err); mErr != nil {
+				progressChan <- racket.PErrorf("[WORKER %v] Error writing manifest: %w", id, mErr)
+			}
+			return
+		}
+		if hash, hErr := calculateSHA256Sum(productFile); hErr == nil {
+			_ = manifest.SetProduct(pdfSrc, productFile, hash)
+		}
+		if mErr := manifest.Transition(pdfSrc, StateDone, nil); mErr != nil {
+			progressChan <- racket.PErrorf("[WORKER %v] Error writing manifest: %w", id, mErr)
+		}
+	}()
+
 	if w.GetBool("dupes") {
 		defer resolveDupes(id, w.GetString("pdf"), &productFile, progressChan)
 	}
@@ -297,6 +497,11 @@ func ripFixWorkFunc(id any, w racket.Work, progressChan chan<- racket.Progress)
 			progressChan <- racket.PErrorf("[WORKER %v] Error: %w", id, err)
 			return
 		}
+		if manifest != nil {
+			if mErr := manifest.Transition(pdfSrc, StateOCRed, nil); mErr != nil {
+				progressChan <- racket.PErrorf("[WORKER %v] Error writing manifest: %w", id, mErr)
+			}
+		}
 	} else {
 		progressChan <- racket.PMessagef("[WORKER %v] %s.pdf found, skipping pdfToTiff and tesseract", id, outFile)
 	}
@@ -305,15 +510,32 @@ func ripFixWorkFunc(id any, w racket.Work, progressChan chan<- racket.Progress)
 	if w.GetString("compress") != "none" {
 		nOutFile := outFile + ".pdf"
 		if !(w.GetBool("skipExisting") && fileExists(compressFile)) {
-			progressChan <- racket.PMessagef("[WORKER %v] compressPdf(%s, %s, %s)", id, w.GetString("compress"), nOutFile, compressFile)
-			err = compressPdf(w.GetString("compress"), nOutFile, compressFile)
+			if w.GetBool("gui") {
+				progressChan <- progressStepStart(id, pdfSrc, StepCompress)
+			}
+			progressChan <- racket.PMessagef("[WORKER %v] compress(%s, %s, %s)", id, w.GetString("compress"), nOutFile, compressFile)
+			render, reErr := newRenderEngine(w.GetString("engine"))
+			if reErr != nil {
+				err = reErr
+				progressChan <- racket.PErrorf("[WORKER %v] Error newRenderEngine '%s': %w", id, w.GetString("engine"), err)
+				return
+			}
+			err = render.Compress(w.GetString("compress"), nOutFile, compressFile)
 			if err != nil {
-				progressChan <- racket.PErrorf("[WORKER %v] Error compressPdf '%s' '%s' -> '%s': %w", id, w.GetString("compress"), nOutFile, compressFile, err)
+				progressChan <- racket.PErrorf("[WORKER %v] Error compress '%s' '%s' -> '%s': %w", id, w.GetString("compress"), nOutFile, compressFile, err)
 				return
 			}
+			if w.GetBool("gui") {
+				progressChan <- progressUnitDone(id, StepCompress, 1, 1)
+			}
 		} else {
 			progressChan <- racket.PMessagef("[WORKER %v] %s found, skipping compressPdf", id, compressFile)
 		}
+		if manifest != nil {
+			if mErr := manifest.Transition(pdfSrc, StateCompressed, nil); mErr != nil {
+				progressChan <- racket.PErrorf("[WORKER %v] Error writing manifest: %w", id, mErr)
+			}
+		}
 		if w.GetBool("clean") {
 			// We are conflicted about this, as it took a lot of work to make that file, and if we don't like the compressed version,
 			// we may want to recompress it using a different setting "manually", but also understand why we're doing this, as 1G PDFs
@@ -322,6 +544,16 @@ func ripFixWorkFunc(id any, w racket.Work, progressChan chan<- racket.Progress)
 		}
 	}
 
+	// If --out is a URL, upload the product there.
+	if remoteOut := w.GetString("remoteOut"); remoteOut != "" {
+		if err = uploadProduct(productFile, remoteOut); err != nil {
+			progressChan <- racket.PErrorf("[WORKER %v] Error uploading '%s' -> '%s': %w", id, productFile, remoteOut, err)
+			return
+		}
+		progressChan <- racket.PMessagef("[WORKER %v] Uploaded '%s' -> '%s'", id, productFile, remoteOut)
+		uploaded = true
+	}
+
 	// Step N celebrate!
 	progressChan <- racket.PMessagef("[WORKER %v] Completed Work! See '%s'", id, productFile)
 	progressChan <- racket.PUpdate(1)
@@ -362,9 +594,11 @@ func resolveDupes(id any, basePDF string, productFile *string, progressChan chan
 				continue
 			}
 			progressChan <- racket.PMessagef("[WORKER %v] Post-process dupe copy of '%s' to '%s' for %s'", id, *productFile, pf, f)
-			_, e := copyFile(*productFile, pf)
-			if e != nil {
-				panic(e)
+			if _, e := copyFile(*productFile, pf); e != nil {
+				// productFile may already be gone (e.g. --clean removed a remote-uploaded
+				// product), so this is reported rather than treated as fatal.
+				progressChan <- racket.PErrorf("[WORKER %v] Error copying dupe '%s' -> '%s': %w", id, *productFile, pf, e)
+				continue
 			}
 		}
 
@@ -374,61 +608,86 @@ func resolveDupes(id any, basePDF string, productFile *string, progressChan chan
 // ripfix is an abstraction to get these steps out of ripFixWorkFunc so it is easier to skip them if needed.
 func ripfix(workerID any, w racket.Work, progressChan chan<- racket.Progress) error {
 	var (
-		err     error
 		outFile = fmt.Sprintf("%s%s%s", w.GetString("out"), strings.TrimSuffix(filepath.Base(w.GetString("pdf")), filepath.Ext(filepath.Base(w.GetString("pdf")))), suffixFixed) // tesseract wants an extensionless filename
 	)
 
-	// Step 4a rip the PDF into TIFFs
-	err = pdfToTiff(w.GetString("pdf"), w.GetString("temp"))
-	if err != nil {
-		return fmt.Errorf("pdftoppm '%s' -> '%s': %w", w.GetString("pdf"), w.GetString("temp"), err)
+	engine, eErr := newOCREngine(w.GetString("ocrEngine"))
+	if eErr != nil {
+		return fmt.Errorf("newOCREngine '%s': %w", w.GetString("ocrEngine"), eErr)
 	}
-	// Step 4b create list of result files, w.GetString("temp")+w.GetString("id")+".lst"
-	progressChan <- racket.PMessagef("[WORKER %v] createTiffList", workerID)
+	render, reErr := newRenderEngine(w.GetString("engine"))
+	if reErr != nil {
+		return fmt.Errorf("newRenderEngine '%s': %w", w.GetString("engine"), reErr)
+	}
+
+	gui := w.GetBool("gui")
 
-	listFile, lErr := createTiffList(w)
-	if lErr != nil {
-		return fmt.Errorf("createTiffList: %w", lErr)
+	// Step 4a rip the PDF into page images
+	if gui {
+		progressChan <- progressStepStart(workerID, w.GetString("pdf"), StepRip)
+	}
+	if err := render.Prepare(w.GetString("pdf"), w.GetString("temp"), workerID, gui, progressChan); err != nil {
+		return fmt.Errorf("prepare '%s' -> '%s': %w", w.GetString("pdf"), w.GetString("temp"), err)
+	}
+	if manifest, ok := w.Get("manifest").(*Manifest); ok && manifest != nil {
+		if mErr := manifest.Transition(w.GetString("pdf"), StateRipped, nil); mErr != nil {
+			progressChan <- racket.PErrorf("[WORKER %v] Error writing manifest: %w", workerID, mErr)
+		}
 	}
 
-	// Step 5 tesseract the TIFFs
-	progressChan <- racket.PMessagef("[WORKER %v] tesseract(%s, %s)", workerID, listFile, outFile)
-	err = tesseract(listFile, outFile)
-	if err != nil {
-		return fmt.Errorf("tesseract '%s' -> '%s': %w", w.GetString("temp"), outFile, err)
+	pages, gErr := globPageImages(w.GetString("temp"))
+	if gErr != nil {
+		return fmt.Errorf("error getting page images '%s': %w", w.GetString("temp"), gErr)
 	}
+	sort.Strings(pages)
 
-	return nil
-}
+	var cache *PageCache
+	if c, ok := w.Get("cache").(*PageCache); ok {
+		cache = c
+	}
 
-// createTiffList assembles a list of -presumably- the TIFF images created by pdfToTiff,
-// writing it to a file that tesseract can read.
-func createTiffList(w racket.Work) (string, error) {
+	// Step 4b OCR each page, picking the best preprocessing threshold per page if enabled
 	var (
-		gfiles []string
-		f      *os.File
-		err    error
+		pagePDFs []string
+		rErr     error
 	)
-	listFile := fmt.Sprintf("%s%s.lst", w.GetString("temp"), w.GetString("id"))
-	gfiles, err = filepath.Glob(w.GetString("temp") + "*.tif")
-	if err != nil {
-		return "", fmt.Errorf("error getting tiffs '%s': %w", w.GetString("temp"), err)
-	}
-	f, err = os.Create(path.Clean(listFile))
-	if err != nil {
-		return "", fmt.Errorf("error creating list file '%s': %w", listFile, err)
+	if gui {
+		progressChan <- progressStepStart(workerID, w.GetString("pdf"), StepOCR)
 	}
-	defer f.Close()
-	for _, line := range gfiles {
-		if _, werr := f.WriteString(line + "\n"); werr != nil {
-			return "", fmt.Errorf("error writing to '%s': %w", listFile, err)
+	if w.GetBool("preprocess") {
+		progressChan <- racket.PMessagef("[WORKER %v] preprocess: selecting best of %d thresholds for %d pages", workerID, len(preprocessThresholds), len(pages))
+		pagePDFs, rErr = bestThresholdPagePDFs(engine, w.GetString("ocrEngine"), pages, cache, workerID, gui, progressChan)
+	} else {
+		pagePDFs = make([]string, len(pages))
+		for i, page := range pages {
+			var pdfPath string
+			pdfPath, _, rErr = recognizePage(engine, w.GetString("ocrEngine"), page, cache)
+			if rErr != nil {
+				break
+			}
+			pagePDFs[i] = pdfPath
+			if gui {
+				progressChan <- progressUnitDone(workerID, StepOCR, i+1, len(pages))
+			}
 		}
 	}
-	return listFile, nil
+	if rErr != nil {
+		return fmt.Errorf("recognize '%s': %w", w.GetString("temp"), rErr)
+	}
+
+	// Step 5 assemble the recognized pages into the final, searchable PDF
+	progressChan <- racket.PMessagef("[WORKER %v] assemble(%d pages, %s)", workerID, len(pagePDFs), outFile)
+	if err := render.Assemble(pagePDFs, outFile); err != nil {
+		return fmt.Errorf("assemble '%s' -> '%s': %w", w.GetString("temp"), outFile, err)
+	}
+
+	return nil
 }
 
 // buildList will possibly recursively (if a glob is provided) create a list of files to assign as work.
-func buildList(files []string, count chan racket.Progress, progressChan chan<- racket.Progress) []string {
+// Entries that are http(s)/s3/sftp URLs are downloaded into destDir first, and the
+// resulting local path is used from then on.
+func buildList(files []string, destDir string, count chan racket.Progress, progressChan chan<- racket.Progress) []string {
 	l := make([]string, 0)
 	for _, file := range files {
 		//fmt.Printf("[FILE] %s\n", file)
@@ -436,12 +695,20 @@ func buildList(files []string, count chan racket.Progress, progressChan chan<- r
 			// we don't want to process the output of previous processes!
 			continue
 		}
+		if remoteScheme(file) != "" {
+			local, err := downloadPDF(file, destDir)
+			if err != nil {
+				panic(err)
+			}
+			progressChan <- racket.PMessagef("[BUILDLIST] Downloaded '%s' -> '%s'", file, local)
+			file = local
+		}
 		if strings.Contains(file, "*") || strings.Contains(file, "?") {
 			gfiles, err := filepath.Glob(file)
 			if err != nil {
 				panic(err)
 			}
-			l = append(l, buildList(gfiles, nil, progressChan)...) // recursion, but don't send the chan!
+			l = append(l, buildList(gfiles, destDir, nil, progressChan)...) // recursion, but don't send the chan!
 		} else if s, err := os.Stat(file); err != nil {
 			// We we can't stat the thing, something is very wrong.
 			panic(fmt.Errorf("file %s cannot be found: %w", file, err))
@@ -479,65 +746,72 @@ func buildList(files []string, count chan racket.Progress, progressChan chan<- r
 	return l
 }
 
-// calculateSHA256Sum calculates the SHA-256 checksum of a file.
-func calculateSHA256Sum(filePath string) (string, error) {
-	//#nosec G304 -- Yes, but no.
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close() // Ensure the file is closed when the function exits
-
-	hash := sha256.New() // Create a new SHA-256 hash function
-
-	// Copy the file's content into the hash function
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", fmt.Errorf("failed to copy file content to hash: %w", err)
+// globPageImages finds the page images a RenderEngine's Prepare wrote into dir.
+// --engine=external always writes TIFFs; --engine=native writes whatever format the
+// source PDF's embedded images already were in, including "jpx" for JPEG2000-encoded
+// (JPXDecode) images, which pdfcpu's image extraction can emit for scanned PDFs.
+func globPageImages(dir string) ([]string, error) {
+	var pages []string
+	for _, ext := range []string{"tif", "tiff", "jpg", "jpeg", "png", "jpx"} {
+		matches, err := filepath.Glob(dir + "*." + ext)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, matches...)
 	}
-
-	// Get the final hash sum and encode it to a hexadecimal string
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return pages, nil
 }
 
-// copyFile ... copies a file.
-func copyFile(src, dst string) (int64, error) {
-	//#nosec G304 - Open the source file for reading
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return 0, err
+// pdfToTiff constructs a pdftoppm Command to extract PDF pages as TIFF images. When gui
+// is set, it runs pdftoppm with -progress and reports each completed page via
+// progressUnitDone, so a worker's row no longer sits at "rip 0/0" for the whole rip
+// step; -progress's exact output isn't stable across poppler versions, so any stderr
+// line that doesn't start with a page number is just ignored rather than erroring.
+func pdfToTiff(pdf, outFolder string, workerID any, gui bool, progressChan chan<- racket.Progress) error {
+	if !gui {
+		return simpleRun("pdftoppm", "-tiff", "-r", "300", pdf, outFolder+"page")
 	}
-	defer sourceFile.Close() // Ensure the source file is closed
 
-	// Get file info to preserve permissions
-	sourceFileInfo, err := sourceFile.Stat()
-	if err != nil {
-		return 0, err
+	total, cErr := api.PageCountFile(pdf)
+	if cErr != nil {
+		return fmt.Errorf("counting pages in '%s': %w", pdf, cErr)
 	}
 
-	//#nosec G304 - Create the destination file with the same permissions as the source
-	destinationFile, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, sourceFileInfo.Mode())
+	cmd := exec.Command("pdftoppm", "-tiff", "-r", "300", "-progress", pdf, outFolder+"page")
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return 0, err
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting pdftoppm: %w", err)
 	}
-	defer destinationFile.Close() // Ensure the destination file is closed
 
-	// Copy the contents from source to destination
-	bytesCopied, err := io.Copy(destinationFile, sourceFile)
-	if err != nil {
-		return 0, err
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		if page, ok := leadingInt(scanner.Text()); ok {
+			progressChan <- progressUnitDone(workerID, StepRip, page, total)
+		}
 	}
 
-	return bytesCopied, nil
+	return cmd.Wait()
 }
 
-// pdfToTiff constructs a pdftoppm Command to extract PDF pages as TIFF images.
-func pdfToTiff(pdf string, outFolder string) error {
-	return simpleRun("pdftoppm", "-tiff", "-r", "300", pdf, outFolder+"page")
+// leadingInt returns the leading run of digits in s as an int, if s starts with one.
+func leadingInt(s string) (int, bool) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:i])
+	return n, err == nil
 }
 
-// tesseract constructs a tesseract Command to do OCR on the TIFF images and reassemble them as a PDF.
-func tesseract(fileList, outpath string) error {
-	return simpleRun("tesseract", fileList, outpath, "pdf")
+// pdfUnite constructs a pdfunite Command to merge ordered single-page PDFs into one.
+func pdfUnite(pagePDFs []string, outpath string) error {
+	return simpleRun("pdfunite", append(append([]string{}, pagePDFs...), outpath)...)
 }
 
 // compress again pulls apart the PDF, an compresses the PDF using ps2pdf
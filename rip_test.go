@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestLeadingInt(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   int
+		wantOk bool
+	}{
+		{name: "bare number", in: "3", want: 3, wantOk: true},
+		{name: "number then text", in: "12 of 40", want: 12, wantOk: true},
+		{name: "no leading digits", in: "Page 3", wantOk: false},
+		{name: "empty", in: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := leadingInt(tt.in)
+			if ok != tt.wantOk {
+				t.Fatalf("leadingInt(%q) ok = %v, want %v", tt.in, ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Fatalf("leadingInt(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}